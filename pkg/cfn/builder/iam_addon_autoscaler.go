@@ -0,0 +1,51 @@
+package builder
+
+func init() {
+	RegisterAddonPolicy(&autoScalerAddonPolicy{})
+}
+
+// autoScalerAddonPolicy lets the cluster-autoscaler describe and scale the node group's ASG, for
+// WithAddonPolicies.AutoScaler. The mutating actions are scoped to ASGs tagged for this cluster so
+// that an autoscaler role can't touch an unrelated ASG in the same account.
+type autoScalerAddonPolicy struct{}
+
+func (autoScalerAddonPolicy) Name() string { return "AutoScaler" }
+
+func (autoScalerAddonPolicy) ManagedPolicyARNs(partition string) []string { return nil }
+
+func (autoScalerAddonPolicy) InlineStatements(ctx PolicyContext) []Statement {
+	return []Statement{
+		{
+			// Kept as the pre-registry logical name so upgrading clusters don't have CloudFormation
+			// delete-and-recreate this IAM::Policy resource.
+			CFNName:   "PolicyAutoScaling",
+			Resources: "*",
+			Actions: []string{
+				"autoscaling:DescribeAutoScalingGroups",
+				"autoscaling:DescribeAutoScalingInstances",
+				"autoscaling:DescribeLaunchConfigurations",
+				"autoscaling:DescribeTags",
+				"ec2:DescribeLaunchTemplateVersions",
+			},
+		},
+		{
+			CFNName:   "PolicyAutoScalingMutate",
+			Resources: "*",
+			Actions: []string{
+				"autoscaling:SetDesiredCapacity",
+				"autoscaling:TerminateInstanceInAutoScalingGroup",
+			},
+			Conditions: []iamCondition{
+				{
+					"StringEquals": {
+						"autoscaling:ResourceTag/k8s.io/cluster-autoscaler/" + ctx.ClusterName: "owned",
+					},
+				},
+			},
+		},
+	}
+}
+
+func (autoScalerAddonPolicy) IRSATrust() *TrustPolicy {
+	return &TrustPolicy{Namespace: "kube-system", ServiceAccount: "cluster-autoscaler"}
+}
@@ -0,0 +1,99 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PolicyContext carries the per-cluster values an AddonPolicy needs to render its statements and
+// IRSA trust policy without reaching back into the rest of the builder package.
+type PolicyContext struct {
+	ClusterName     string
+	Partition       string
+	OIDCProviderARN string
+	OIDCProviderURL string
+}
+
+// Statement is one IAM statement an AddonPolicy wants attached, optionally gated by one or more
+// Conditions.
+type Statement struct {
+	Resources  interface{}
+	Actions    []string
+	Conditions []iamCondition
+	// CFNName overrides the generated CloudFormation logical resource name ("Policy<addon>",
+	// "Policy<addon><index>") for this statement. Set it when an addon predates the registry and
+	// must keep its historical logical ID so upgrading clusters don't have CloudFormation
+	// delete-and-recreate the IAM::Policy resource.
+	CFNName string
+}
+
+// TrustPolicy describes the well-known ServiceAccount an addon's IRSA role should trust.
+type TrustPolicy struct {
+	Namespace      string
+	ServiceAccount string
+}
+
+// AddonPolicy is the interface each supported addon (AutoScaler, CertManager, ALBIngress, ...)
+// implements so that createRole no longer needs a hardcoded `if api.IsEnabled(...)` branch per
+// addon. Third parties and forks can add support for new addons (Karpenter, EFS CSI driver, Node
+// Termination Handler, ...) by implementing this interface and calling RegisterAddonPolicy from
+// an init() function, without touching this package.
+type AddonPolicy interface {
+	// Name is the WithAddonPolicies field name, e.g. "AutoScaler", used as the registry key and as
+	// the suffix of the generated CloudFormation resource/statement names.
+	Name() string
+	// ManagedPolicyARNs returns any AWS-managed policy ARNs (in the given partition) this addon
+	// needs attached, in addition to its inline statements. Most addons return nil.
+	ManagedPolicyARNs(partition string) []string
+	// InlineStatements returns the IAM statements this addon needs, scoped using ctx.
+	InlineStatements(ctx PolicyContext) []Statement
+	// IRSATrust returns the well-known ServiceAccount this addon should be trusted for when its
+	// policy is attached to a dedicated IRSA role instead of the shared node role. Addons without a
+	// well-known ServiceAccount (and so can't support AddonPolicyAttachModeServiceAccount) return nil.
+	IRSATrust() *TrustPolicy
+}
+
+// addonPolicyRegistry holds every AddonPolicy registered via RegisterAddonPolicy, keyed by Name().
+var addonPolicyRegistry = map[string]AddonPolicy{}
+
+// RegisterAddonPolicy makes an AddonPolicy available to createRole under its Name(). It's intended
+// to be called from an init() function in the file implementing the AddonPolicy, mirroring how
+// Kubernetes client-go's scheme registration and similar plugin registries work elsewhere in this
+// codebase.
+func RegisterAddonPolicy(p AddonPolicy) {
+	addonPolicyRegistry[p.Name()] = p
+}
+
+// lookupAddonPolicy returns the registered AddonPolicy for name, or an error if nothing has
+// registered under that name - e.g. a typo in WithAddonPolicies, or a fork that removed an addon's
+// registration file without updating its ClusterConfig schema.
+func lookupAddonPolicy(name string) (AddonPolicy, error) {
+	p, ok := addonPolicyRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("no addon policy registered for %q", name)
+	}
+	return p, nil
+}
+
+// RegisteredAddonNames returns the Name() of every AddonPolicy registered via RegisterAddonPolicy,
+// sorted for deterministic iteration (e.g. when collecting ManagedPolicyARNs across addons).
+func RegisteredAddonNames() []string {
+	names := make([]string, 0, len(addonPolicyRegistry))
+	for name := range addonPolicyRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateAddonPolicyNames checks that every name has a registered AddonPolicy, so that an open
+// `WithAddonPolicies map[string]bool` in ClusterConfig can be validated against the registry
+// instead of a fixed struct of known fields.
+func ValidateAddonPolicyNames(names []string) error {
+	for _, name := range names {
+		if _, err := lookupAddonPolicy(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package builder
+
+import "testing"
+
+// fakeAddonPolicy is a minimal AddonPolicy used to exercise the registry without depending on a
+// real addon implementation.
+type fakeAddonPolicy struct {
+	name  string
+	trust *TrustPolicy
+}
+
+func (p *fakeAddonPolicy) Name() string                                { return p.name }
+func (p *fakeAddonPolicy) ManagedPolicyARNs(partition string) []string { return nil }
+func (p *fakeAddonPolicy) InlineStatements(ctx PolicyContext) []Statement {
+	return []Statement{{Resources: "*", Actions: []string{"fake:Action"}}}
+}
+func (p *fakeAddonPolicy) IRSATrust() *TrustPolicy { return p.trust }
+
+func TestRegisterAndLookupAddonPolicy(t *testing.T) {
+	defer delete(addonPolicyRegistry, "FakeAddon")
+
+	if _, err := lookupAddonPolicy("FakeAddon"); err == nil {
+		t.Fatalf("expected an error looking up an unregistered addon")
+	}
+
+	policy := &fakeAddonPolicy{name: "FakeAddon"}
+	RegisterAddonPolicy(policy)
+
+	got, err := lookupAddonPolicy("FakeAddon")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != policy {
+		t.Fatalf("lookupAddonPolicy returned a different instance than was registered")
+	}
+}
+
+func TestRegisteredAddonNamesSorted(t *testing.T) {
+	defer delete(addonPolicyRegistry, "ZZZFakeAddon")
+	defer delete(addonPolicyRegistry, "AAAFakeAddon")
+	RegisterAddonPolicy(&fakeAddonPolicy{name: "ZZZFakeAddon"})
+	RegisterAddonPolicy(&fakeAddonPolicy{name: "AAAFakeAddon"})
+
+	names := RegisteredAddonNames()
+	zIdx, aIdx := -1, -1
+	for i, name := range names {
+		switch name {
+		case "ZZZFakeAddon":
+			zIdx = i
+		case "AAAFakeAddon":
+			aIdx = i
+		}
+	}
+	if aIdx == -1 || zIdx == -1 || aIdx > zIdx {
+		t.Fatalf("expected RegisteredAddonNames to be sorted, got %v", names)
+	}
+}
+
+func TestValidateAddonPolicyNames(t *testing.T) {
+	defer delete(addonPolicyRegistry, "FakeAddon")
+	RegisterAddonPolicy(&fakeAddonPolicy{name: "FakeAddon"})
+
+	if err := ValidateAddonPolicyNames([]string{"FakeAddon"}); err != nil {
+		t.Errorf("unexpected error for a registered addon: %v", err)
+	}
+	if err := ValidateAddonPolicyNames([]string{"FakeAddon", "NotRegistered"}); err == nil {
+		t.Errorf("expected an error when one of the names isn't registered")
+	}
+}
@@ -0,0 +1,32 @@
+package builder
+
+func init() {
+	RegisterAddonPolicy(&xrayAddonPolicy{})
+}
+
+// xrayAddonPolicy lets worker nodes running the X-Ray daemon ship trace data and fetch sampling
+// rules, for WithAddonPolicies.XRay.
+type xrayAddonPolicy struct{}
+
+func (xrayAddonPolicy) Name() string { return "XRay" }
+
+func (xrayAddonPolicy) ManagedPolicyARNs(partition string) []string { return nil }
+
+func (xrayAddonPolicy) InlineStatements(ctx PolicyContext) []Statement {
+	return []Statement{
+		{
+			Resources: "*",
+			Actions: []string{
+				"xray:PutTraceSegments",
+				"xray:PutTelemetryRecords",
+				"xray:GetSamplingRules",
+				"xray:GetSamplingTargets",
+				"xray:GetSamplingStatisticSummaries",
+			},
+		},
+	}
+}
+
+func (xrayAddonPolicy) IRSATrust() *TrustPolicy {
+	return &TrustPolicy{Namespace: "kube-system", ServiceAccount: "xray-daemon"}
+}
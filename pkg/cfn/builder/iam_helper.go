@@ -6,6 +6,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	iampolicies "github.com/weaveworks/eksctl/pkg/cfn/builder/iam_policies"
 	cft "github.com/weaveworks/eksctl/pkg/cfn/template"
 	gfn "github.com/weaveworks/goformation/v4/cloudformation"
 	gfniam "github.com/weaveworks/goformation/v4/cloudformation/iam"
@@ -13,14 +14,108 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// iamCondition is a single IAM Condition block, e.g.
+//   map[string]map[string]interface{}{"StringEquals": {"autoscaling:ResourceTag/k8s.io/cluster-autoscaler/foo": "owned"}}
+// rendered verbatim into the statement's "Condition" key.
+type iamCondition = map[string]map[string]interface{}
+
 type cfnTemplate interface {
-	attachAllowPolicy(name string, refRole *gfnt.Value, resources interface{}, actions []string)
+	// attachAllowPolicy attaches an Allow statement for the given resources/actions to refRole. An
+	// optional iamCondition can be passed to scope the statement down further, e.g. restricting an
+	// action to resources carrying a specific tag; omit it for an unconditional statement.
+	attachAllowPolicy(name string, refRole *gfnt.Value, resources interface{}, actions []string, conditions ...iamCondition)
 	newResource(name string, resource gfn.Resource) *gfnt.Value
 }
 
-// createRole creates an IAM role with policies required for the worker nodes and addons
-func createRole(cfnTemplate cfnTemplate, clusterIAMConfig *api.ClusterIAM, iamConfig *api.NodeGroupIAM, managed, enableSSM bool) error {
-	managedPolicyARNs, err := makeManagedPolicies(clusterIAMConfig, iamConfig, managed, enableSSM)
+const (
+	partitionAWS      = "aws"
+	partitionAWSCN    = "aws-cn"
+	partitionAWSUsGov = "aws-us-gov"
+	partitionAWSISO   = "aws-iso"
+	partitionAWSISOB  = "aws-iso-b"
+)
+
+// partitionForRegion returns the AWS ARN partition that a given EC2 region belongs to, so that
+// generated ARNs are correct for GovCloud, China, and the ISO/ISO-B regions, as well as the
+// standard "aws" partition.
+func partitionForRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return partitionAWSUsGov
+	case strings.HasPrefix(region, "cn-"):
+		return partitionAWSCN
+	case strings.HasPrefix(region, "us-isob-"):
+		return partitionAWSISOB
+	case strings.HasPrefix(region, "us-iso-"):
+		return partitionAWSISO
+	default:
+		return partitionAWS
+	}
+}
+
+// validateARNPartition checks that a user-supplied ARN belongs to the partition eksctl has
+// derived for the cluster's region, so that e.g. a `aws` policy ARN isn't silently accepted for a
+// GovCloud cluster.
+func validateARNPartition(userARN, partition string) error {
+	parsed, err := arn.Parse(userARN)
+	if err != nil {
+		return err
+	}
+	if parsed.Partition != partition {
+		return fmt.Errorf("ARN %q is in partition %q, expected %q for this cluster's region", userARN, parsed.Partition, partition)
+	}
+	return nil
+}
+
+// policyAttacher adapts attachAddonPolicy to the iampolicies.PolicyAttacher interface, capturing
+// the first error so that addXPolicies functions (which don't return errors themselves) can still
+// report a failed attach back to createRole.
+type policyAttacher struct {
+	attach func(cfnName string, resources interface{}, actions []string, conditions ...iampolicies.Condition) error
+	err    error
+}
+
+func (a *policyAttacher) AttachAllowPolicy(name string, resources interface{}, actions []string, conditions ...iampolicies.Condition) {
+	if a.err != nil {
+		return
+	}
+	a.err = a.attach(name, resources, actions, conditions...)
+}
+
+// createRole creates an IAM role with policies required for the worker nodes and addons. When
+// clusterIAMConfig.WithOIDC is enabled and addonPolicyMode is AddonPolicyAttachModeServiceAccount,
+// addons that have a well-known ServiceAccount registered get their own IRSA role instead of
+// widening the node instance role.
+func createRole(cfnTemplate cfnTemplate, clusterIAMConfig *api.ClusterIAM, iamConfig *api.NodeGroupIAM, clusterName, region string, addonPolicyMode AddonPolicyAttachMode, oidcProviderARN, oidcProviderURL string, managed, enableSSM bool) error {
+	partition := partitionForRegion(region)
+
+	if iamConfig.InstanceRolePermissionsBoundary != "" {
+		if err := validateARNPartition(iamConfig.InstanceRolePermissionsBoundary, partition); err != nil {
+			return err
+		}
+	}
+
+	if iamConfig.InstanceRoleARN != "" {
+		if err := validateARNPartition(iamConfig.InstanceRoleARN, partition); err != nil {
+			return err
+		}
+	}
+
+	useServiceAccountRoles := api.IsEnabled(clusterIAMConfig.WithOIDC) && addonPolicyMode == AddonPolicyAttachModeServiceAccount
+
+	var registeredAddonManagedPolicyARNs []string
+	for _, addon := range RegisteredAddonNames() {
+		if !registeredAddonEnabled(iamConfig, addon) {
+			continue
+		}
+		policy, err := lookupAddonPolicy(addon)
+		if err != nil {
+			return err
+		}
+		registeredAddonManagedPolicyARNs = append(registeredAddonManagedPolicyARNs, policy.ManagedPolicyARNs(partition)...)
+	}
+
+	managedPolicyARNs, err := makeManagedPolicies(partition, clusterIAMConfig, iamConfig, managed, enableSSM, registeredAddonManagedPolicyARNs)
 	if err != nil {
 		return err
 	}
@@ -38,243 +133,233 @@ func createRole(cfnTemplate cfnTemplate, clusterIAMConfig *api.ClusterIAM, iamCo
 		role.PermissionsBoundary = gfnt.NewString(iamConfig.InstanceRolePermissionsBoundary)
 	}
 
+	for _, policy := range iamConfig.AttachPolicies {
+		role.Policies = append(role.Policies, gfniam.Role_Policy{
+			PolicyName:     gfnt.NewString(policy.Name),
+			PolicyDocument: policy.Document,
+		})
+	}
+
 	refIR := cfnTemplate.newResource(cfnIAMInstanceRoleName, &role)
 
-	if api.IsEnabled(iamConfig.WithAddonPolicies.AutoScaler) {
-		cfnTemplate.attachAllowPolicy("PolicyAutoScaling", refIR, "*",
-			[]string{
-				"autoscaling:DescribeAutoScalingGroups",
-				"autoscaling:DescribeAutoScalingInstances",
-				"autoscaling:DescribeLaunchConfigurations",
-				"autoscaling:DescribeTags",
-				"autoscaling:SetDesiredCapacity",
-				"autoscaling:TerminateInstanceInAutoScalingGroup",
-				"ec2:DescribeLaunchTemplateVersions",
-			},
-		)
+	// addonServiceAccountRoles caches the IRSA role created for each addon, so that addons with more
+	// than one inline statement (e.g. CertManager, AutoScaler) attach every statement to the same
+	// role instead of each call declaring a fresh "IAMServiceAccountPolicy<addon>" resource that
+	// overwrites the last.
+	addonServiceAccountRoles := map[string]*gfnt.Value{}
+
+	// attachAddonPolicy attaches an addon's policy under cfnName to the shared node role, unless
+	// useServiceAccountRoles is set and addon has a well-known ServiceAccount registered, in which
+	// case it gets its own IRSA role instead and the node role is left untouched.
+	attachAddonPolicy := func(cfnName, addon string, resources interface{}, actions []string, conditions ...iamCondition) error {
+		if useServiceAccountRoles {
+			if trust := resolveAddonTrust(addon); trust != nil {
+				refRole, ok := addonServiceAccountRoles[addon]
+				if !ok {
+					var err error
+					refRole, err = createAddonServiceAccountRole(cfnTemplate, oidcProviderARN, oidcProviderURL, addon, *trust)
+					if err != nil {
+						return err
+					}
+					addonServiceAccountRoles[addon] = refRole
+				}
+				cfnTemplate.attachAllowPolicy(cfnName, refRole, resources, actions, conditions...)
+				return nil
+			}
+		}
+		cfnTemplate.attachAllowPolicy(cfnName, refIR, resources, actions, conditions...)
+		return nil
 	}
 
-	if api.IsEnabled(iamConfig.WithAddonPolicies.CertManager) {
-		cfnTemplate.attachAllowPolicy("PolicyCertManagerChangeSet", refIR, addARNPartitionPrefix("route53:::hostedzone/*"),
-			[]string{
-				"route53:ChangeResourceRecordSets",
-			},
-		)
-
-		hostedZonePolicy := []string{
-			"route53:ListResourceRecordSets",
-			"route53:ListHostedZonesByName",
+	// attachRegisteredAddonPolicy looks up addon in the AddonPolicy registry and attaches each of
+	// its inline statements via attachAddonPolicy, so registered addons are routed through the same
+	// node-role/IRSA selection as the addons that haven't been migrated off the legacy cascade yet.
+	policyCtx := PolicyContext{ClusterName: clusterName, Partition: partition, OIDCProviderARN: oidcProviderARN, OIDCProviderURL: oidcProviderURL}
+	attachRegisteredAddonPolicy := func(addon string) error {
+		policy, err := lookupAddonPolicy(addon)
+		if err != nil {
+			return err
+		}
+		for i, stmt := range policy.InlineStatements(policyCtx) {
+			cfnName := stmt.CFNName
+			if cfnName == "" {
+				cfnName = fmt.Sprintf("Policy%s", addon)
+				if i > 0 {
+					cfnName = fmt.Sprintf("Policy%s%d", addon, i)
+				}
+			}
+			if err := attachAddonPolicy(cfnName, addon, stmt.Resources, stmt.Actions, stmt.Conditions...); err != nil {
+				return err
+			}
 		}
+		return nil
+	}
+
+	// addonPolicyAttacher adapts attachAddonPolicy to the iampolicies.PolicyAttacher interface so
+	// that the refactored addXPolicies functions can attach statements without knowing about the
+	// CloudFormation template, the node role, or IRSA routing.
+	addonPolicyAttacher := func(addon string) *policyAttacher {
+		return &policyAttacher{attach: func(cfnName string, resources interface{}, actions []string, conditions ...iampolicies.Condition) error {
+			return attachAddonPolicy(cfnName, addon, resources, actions, conditions...)
+		}}
+	}
+
+	// addonPolicyBuilder returns a PolicyBuilder for addon, and the policyAttacher backing it so the
+	// caller can check for an attach error once all its statements have been added. IRSAEnabled is
+	// set when useServiceAccountRoles means addon's statements will land on its own IRSA role rather
+	// than the shared node role.
+	addonPolicyBuilder := func(addon string) (*iampolicies.PolicyBuilder, *policyAttacher) {
+		attacher := addonPolicyAttacher(addon)
+		return &iampolicies.PolicyBuilder{
+			Attacher:       attacher,
+			ClusterName:    clusterName,
+			LeastPrivilege: iamConfig.LeastPrivilege,
+			Partition:      partition,
+			IRSAEnabled:    useServiceAccountRoles && resolveAddonTrust(addon) != nil,
+		}, attacher
+	}
 
-		if api.IsEnabled(iamConfig.WithAddonPolicies.ExternalDNS) {
-			hostedZonePolicy = append(hostedZonePolicy, "route53:ListHostedZones", "route53:ListTagsForResource")
+	if api.IsEnabled(iamConfig.WithAddonPolicies.AutoScaler) {
+		if err := attachRegisteredAddonPolicy("AutoScaler"); err != nil {
+			return err
 		}
+	}
 
-		cfnTemplate.attachAllowPolicy("PolicyCertManagerHostedZones", refIR, "*", hostedZonePolicy)
-		cfnTemplate.attachAllowPolicy("PolicyCertManagerGetChange", refIR, addARNPartitionPrefix("route53:::change/*"),
-			[]string{
-				"route53:GetChange",
-			},
-		)
+	if api.IsEnabled(iamConfig.WithAddonPolicies.CertManager) {
+		builder, attacher := addonPolicyBuilder("CertManager")
+		iampolicies.AddCertManagerPolicies(builder, api.IsEnabled(iamConfig.WithAddonPolicies.ExternalDNS))
+		if attacher.err != nil {
+			return attacher.err
+		}
 	} else if api.IsEnabled(iamConfig.WithAddonPolicies.ExternalDNS) {
-		cfnTemplate.attachAllowPolicy("PolicyExternalDNSChangeSet", refIR, addARNPartitionPrefix("route53:::hostedzone/*"),
-			[]string{
-				"route53:ChangeResourceRecordSets",
-			},
-		)
-		cfnTemplate.attachAllowPolicy("PolicyExternalDNSHostedZones", refIR, "*",
-			[]string{
-				"route53:ListHostedZones",
-				"route53:ListResourceRecordSets",
-				"route53:ListTagsForResource",
-			},
-		)
-	}
-
-	appMeshActions := []string{
-		"servicediscovery:CreateService",
-		"servicediscovery:DeleteService",
-		"servicediscovery:GetService",
-		"servicediscovery:GetInstance",
-		"servicediscovery:RegisterInstance",
-		"servicediscovery:DeregisterInstance",
-		"servicediscovery:ListInstances",
-		"servicediscovery:ListNamespaces",
-		"servicediscovery:ListServices",
-		"servicediscovery:GetInstancesHealthStatus",
-		"servicediscovery:UpdateInstanceCustomHealthStatus",
-		"servicediscovery:GetOperation",
-		"route53:GetHealthCheck",
-		"route53:CreateHealthCheck",
-		"route53:UpdateHealthCheck",
-		"route53:ChangeResourceRecordSets",
-		"route53:DeleteHealthCheck",
+		builder, attacher := addonPolicyBuilder("ExternalDNS")
+		iampolicies.AddExternalDNSPolicies(builder)
+		if attacher.err != nil {
+			return attacher.err
+		}
 	}
 
 	if api.IsEnabled(iamConfig.WithAddonPolicies.AppMesh) {
-		cfnTemplate.attachAllowPolicy("PolicyAppMesh", refIR, "*",
-			append(appMeshActions, "appmesh:*"),
-		)
+		builder, attacher := addonPolicyBuilder("AppMesh")
+		iampolicies.AddAppMeshPolicies(builder, false)
+		if attacher.err != nil {
+			return attacher.err
+		}
 	}
 
 	if api.IsEnabled(iamConfig.WithAddonPolicies.AppMeshPreview) {
-		cfnTemplate.attachAllowPolicy("PolicyAppMeshPreview", refIR, "*",
-			append(appMeshActions, "appmesh-preview:*"),
-		)
+		builder, attacher := addonPolicyBuilder("AppMeshPreview")
+		iampolicies.AddAppMeshPolicies(builder, true)
+		if attacher.err != nil {
+			return attacher.err
+		}
 	}
 
 	if api.IsEnabled(iamConfig.WithAddonPolicies.EBS) {
-		cfnTemplate.attachAllowPolicy("PolicyEBS", refIR, "*",
-			[]string{
-				"ec2:AttachVolume",
-				"ec2:CreateSnapshot",
-				"ec2:CreateTags",
-				"ec2:CreateVolume",
-				"ec2:DeleteSnapshot",
-				"ec2:DeleteTags",
-				"ec2:DeleteVolume",
-				"ec2:DescribeAvailabilityZones",
-				"ec2:DescribeInstances",
-				"ec2:DescribeSnapshots",
-				"ec2:DescribeTags",
-				"ec2:DescribeVolumes",
-				"ec2:DescribeVolumesModifications",
-				"ec2:DetachVolume",
-				"ec2:ModifyVolume",
-			},
-		)
+		builder, attacher := addonPolicyBuilder("EBS")
+		iampolicies.AddEBSPolicies(builder)
+		if attacher.err != nil {
+			return attacher.err
+		}
 	}
 
 	if api.IsEnabled(iamConfig.WithAddonPolicies.FSX) {
-		cfnTemplate.attachAllowPolicy("PolicyFSX", refIR, "*",
-			[]string{
-				"fsx:*",
-			},
-		)
-		cfnTemplate.attachAllowPolicy("PolicyServiceLinkRole", refIR, addARNPartitionPrefix("iam::*:role/aws-service-role/*"),
-			[]string{
-				"iam:CreateServiceLinkedRole",
-				"iam:AttachRolePolicy",
-				"iam:PutRolePolicy",
-			},
-		)
+		builder, attacher := addonPolicyBuilder("FSX")
+		iampolicies.AddFSXPolicies(builder)
+		if attacher.err != nil {
+			return attacher.err
+		}
 	}
 
 	if api.IsEnabled(iamConfig.WithAddonPolicies.EFS) {
-		cfnTemplate.attachAllowPolicy("PolicyEFS", refIR, "*",
-			[]string{
-				"elasticfilesystem:*",
-			},
-		)
-		cfnTemplate.attachAllowPolicy("PolicyEFSEC2", refIR, "*",
-			[]string{
-				"ec2:DescribeSubnets",
-				"ec2:CreateNetworkInterface",
-				"ec2:DescribeNetworkInterfaces",
-				"ec2:DeleteNetworkInterface",
-				"ec2:ModifyNetworkInterfaceAttribute",
-				"ec2:DescribeNetworkInterfaceAttribute",
-			},
-		)
+		builder, attacher := addonPolicyBuilder("EFS")
+		iampolicies.AddEFSPolicies(builder)
+		if attacher.err != nil {
+			return attacher.err
+		}
 	}
 
 	if api.IsEnabled(iamConfig.WithAddonPolicies.ALBIngress) {
-		cfnTemplate.attachAllowPolicy("PolicyALBIngress", refIR, "*",
-			[]string{
-				"acm:DescribeCertificate",
-				"acm:ListCertificates",
-				"acm:GetCertificate",
-				"ec2:AuthorizeSecurityGroupIngress",
-				"ec2:CreateSecurityGroup",
-				"ec2:CreateTags",
-				"ec2:DeleteTags",
-				"ec2:DeleteSecurityGroup",
-				"ec2:DescribeAccountAttributes",
-				"ec2:DescribeAddresses",
-				"ec2:DescribeInstances",
-				"ec2:DescribeInstanceStatus",
-				"ec2:DescribeInternetGateways",
-				"ec2:DescribeNetworkInterfaces",
-				"ec2:DescribeSecurityGroups",
-				"ec2:DescribeSubnets",
-				"ec2:DescribeTags",
-				"ec2:DescribeVpcs",
-				"ec2:ModifyInstanceAttribute",
-				"ec2:ModifyNetworkInterfaceAttribute",
-				"ec2:RevokeSecurityGroupIngress",
-				"elasticloadbalancing:AddListenerCertificates",
-				"elasticloadbalancing:AddTags",
-				"elasticloadbalancing:CreateListener",
-				"elasticloadbalancing:CreateLoadBalancer",
-				"elasticloadbalancing:CreateRule",
-				"elasticloadbalancing:CreateTargetGroup",
-				"elasticloadbalancing:DeleteListener",
-				"elasticloadbalancing:DeleteLoadBalancer",
-				"elasticloadbalancing:DeleteRule",
-				"elasticloadbalancing:DeleteTargetGroup",
-				"elasticloadbalancing:DeregisterTargets",
-				"elasticloadbalancing:DescribeListenerCertificates",
-				"elasticloadbalancing:DescribeListeners",
-				"elasticloadbalancing:DescribeLoadBalancers",
-				"elasticloadbalancing:DescribeLoadBalancerAttributes",
-				"elasticloadbalancing:DescribeRules",
-				"elasticloadbalancing:DescribeSSLPolicies",
-				"elasticloadbalancing:DescribeTags",
-				"elasticloadbalancing:DescribeTargetGroups",
-				"elasticloadbalancing:DescribeTargetGroupAttributes",
-				"elasticloadbalancing:DescribeTargetHealth",
-				"elasticloadbalancing:ModifyListener",
-				"elasticloadbalancing:ModifyLoadBalancerAttributes",
-				"elasticloadbalancing:ModifyRule",
-				"elasticloadbalancing:ModifyTargetGroup",
-				"elasticloadbalancing:ModifyTargetGroupAttributes",
-				"elasticloadbalancing:RegisterTargets",
-				"elasticloadbalancing:RemoveListenerCertificates",
-				"elasticloadbalancing:RemoveTags",
-				"elasticloadbalancing:SetIpAddressType",
-				"elasticloadbalancing:SetSecurityGroups",
-				"elasticloadbalancing:SetSubnets",
-				"elasticloadbalancing:SetWebACL",
-				"iam:CreateServiceLinkedRole",
-				"iam:GetServerCertificate",
-				"iam:ListServerCertificates",
-				"waf-regional:GetWebACLForResource",
-				"waf-regional:GetWebACL",
-				"waf-regional:AssociateWebACL",
-				"waf-regional:DisassociateWebACL",
-				"tag:GetResources",
-				"tag:TagResources",
-				"waf:GetWebACL",
-				"wafv2:GetWebACL",
-				"wafv2:GetWebACLForResource",
-				"wafv2:AssociateWebACL",
-				"wafv2:DisassociateWebACL",
-				"shield:DescribeProtection",
-				"shield:GetSubscriptionState",
-				"shield:DeleteProtection",
-				"shield:CreateProtection",
-				"shield:DescribeSubscription",
-				"shield:ListProtections",
-			},
-		)
+		builder, attacher := addonPolicyBuilder("ALBIngress")
+		iampolicies.AddALBIngressPolicies(builder)
+		if attacher.err != nil {
+			return attacher.err
+		}
 	}
 
 	if api.IsEnabled(iamConfig.WithAddonPolicies.XRay) {
-		cfnTemplate.attachAllowPolicy("PolicyXRay", refIR, "*",
-			[]string{
-				"xray:PutTraceSegments",
-				"xray:PutTelemetryRecords",
-				"xray:GetSamplingRules",
-				"xray:GetSamplingTargets",
-				"xray:GetSamplingStatisticSummaries",
-			},
-		)
+		if err := attachRegisteredAddonPolicy("XRay"); err != nil {
+			return err
+		}
 	}
+
 	return nil
 }
 
-func makeManagedPolicies(iamCluster *api.ClusterIAM, iamConfig *api.NodeGroupIAM, managed, enableSSM bool) (*gfnt.Value, error) {
+// registeredAddonEnabled reports whether iamConfig.WithAddonPolicies enables the given
+// registry-migrated addon. WithAddonPolicies is a fixed struct rather than a map, so each addon
+// migrated onto the AddonPolicy registry needs a case here alongside its attachRegisteredAddonPolicy
+// call in createRole.
+func registeredAddonEnabled(iamConfig *api.NodeGroupIAM, addon string) bool {
+	switch addon {
+	case "AutoScaler":
+		return api.IsEnabled(iamConfig.WithAddonPolicies.AutoScaler)
+	case "XRay":
+		return api.IsEnabled(iamConfig.WithAddonPolicies.XRay)
+	default:
+		return false
+	}
+}
+
+// requiredManagedPolicyNames returns the AWS-managed policy names eksctl relies on for the worker
+// node to function, used to validate an AttachPolicyARNsExclusive set isn't missing something
+// eksctl would otherwise have added implicitly.
+func requiredManagedPolicyNames(iamCluster *api.ClusterIAM, managed bool) []string {
+	required := append([]string{}, iamDefaultNodePolicies...)
+	if !api.IsEnabled(iamCluster.WithOIDC) {
+		required = append(required, iamPolicyAmazonEKSCNIPolicy)
+	}
+	if managed {
+		required = append(required, iamPolicyAmazonEC2ContainerRegistryReadOnly)
+	}
+	return required
+}
+
+// makeManagedPolicies builds the node role's ManagedPolicyArns list: eksctl's own managed policies
+// (the default/CNI/ECR set only when no AttachPolicyARNs are given), any user-supplied
+// AttachPolicyARNs, and extraManagedPolicyARNs - the ManagedPolicyARNs() any enabled,
+// registry-migrated AddonPolicy asked to have attached alongside its inline statements. With
+// AttachPolicyARNsExclusive set, eksctl adds none of its own policies: the list is AttachPolicyARNs
+// plus extraManagedPolicyARNs, after checking AttachPolicyARNs covers requiredManagedPolicyNames.
+func makeManagedPolicies(partition string, iamCluster *api.ClusterIAM, iamConfig *api.NodeGroupIAM, managed, enableSSM bool, extraManagedPolicyARNs []string) (*gfnt.Value, error) {
 	managedPolicyNames := sets.NewString()
+
+	if api.IsEnabled(iamConfig.AttachPolicyARNsExclusive) {
+		if len(iamConfig.AttachPolicyARNs) == 0 {
+			return nil, fmt.Errorf("iam.attachPolicyARNsExclusive is set but no attachPolicyARNs were provided")
+		}
+		attached := sets.NewString()
+		for _, policyARN := range iamConfig.AttachPolicyARNs {
+			parsedARN, err := arn.Parse(policyARN)
+			if err != nil {
+				return nil, err
+			}
+			if parsedARN.Partition != partition {
+				return nil, fmt.Errorf("ARN %q is in partition %q, expected %q for this cluster's region", policyARN, parsedARN.Partition, partition)
+			}
+			start := strings.IndexRune(parsedARN.Resource, '/')
+			if start == -1 || start+1 == len(parsedARN.Resource) {
+				return nil, fmt.Errorf("failed to find ARN resource name: %s", parsedARN.Resource)
+			}
+			attached.Insert(parsedARN.Resource[start+1:])
+		}
+		if missing := sets.NewString(requiredManagedPolicyNames(iamCluster, managed)...).Difference(attached); missing.Len() > 0 {
+			return nil, fmt.Errorf("attachPolicyARNsExclusive is set but attachPolicyARNs is missing required managed polic%s: %s",
+				map[bool]string{true: "y", false: "ies"}[missing.Len() == 1], strings.Join(missing.List(), ", "))
+		}
+		return gfnt.NewSlice(makeStringSlice(append(append([]string{}, iamConfig.AttachPolicyARNs...), extraManagedPolicyARNs...)...)...), nil
+	}
+
 	if len(iamConfig.AttachPolicyARNs) == 0 {
 		managedPolicyNames.Insert(iamDefaultNodePolicies...)
 		if !api.IsEnabled(iamCluster.WithOIDC) {
@@ -309,6 +394,9 @@ func makeManagedPolicies(iamCluster *api.ClusterIAM, iamConfig *api.NodeGroupIAM
 		if err != nil {
 			return nil, err
 		}
+		if parsedARN.Partition != partition {
+			return nil, fmt.Errorf("ARN %q is in partition %q, expected %q for this cluster's region", policyARN, parsedARN.Partition, partition)
+		}
 		start := strings.IndexRune(parsedARN.Resource, '/')
 		if start == -1 || start+1 == len(parsedARN.Resource) {
 			return nil, fmt.Errorf("failed to find ARN resource name: %s", parsedARN.Resource)
@@ -317,24 +405,53 @@ func makeManagedPolicies(iamCluster *api.ClusterIAM, iamConfig *api.NodeGroupIAM
 		managedPolicyNames.Delete(resourceName)
 	}
 
-	return gfnt.NewSlice(append(
+	return gfnt.NewSlice(append(append(
 		makeStringSlice(iamConfig.AttachPolicyARNs...),
-		makePolicyARNs(managedPolicyNames.List()...)...,
+		makePolicyARNs(partition, managedPolicyNames.List()...)...),
+		makeStringSlice(extraManagedPolicyARNs...)...,
 	)...), nil
 }
 
+// makeStringSlice wraps each of the given strings as a gfnt.Value, suitable for use in a
+// CloudFormation list property.
+func makeStringSlice(strs ...string) []*gfnt.Value {
+	values := make([]*gfnt.Value, len(strs))
+	for i, s := range strs {
+		values[i] = gfnt.NewString(s)
+	}
+	return values
+}
+
+// makePolicyARNs builds the fully-qualified managed policy ARNs, in the given partition, for a
+// set of AWS-managed policy names, e.g. "AmazonEKSWorkerNodePolicy" becomes
+// "arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy".
+func makePolicyARNs(partition string, names ...string) []*gfnt.Value {
+	values := make([]*gfnt.Value, len(names))
+	for i, name := range names {
+		values[i] = gfnt.NewString(fmt.Sprintf("arn:%s:iam::aws:policy/%s", partition, name))
+	}
+	return values
+}
+
 // NormalizeARN returns the ARN with just the last element in the resource path preserved. If the
-// input does not contain at least one forward-slash then the input is returned unmodified.
+// input does not contain at least one forward-slash then the input is returned unmodified. The
+// ARN is parsed rather than split on ":" so the partition, service, region, and account ID -
+// including GovCloud/China/ISO partitions - are carried through unchanged.
 //
 // When providing an existing instanceRoleARN that contains a path other than "/", nodes may
 // fail to join the cluster as the AWS IAM Authenticator does not recognize such ARNs declared in
 // the aws-auth ConfigMap.
 //
 // See: https://docs.aws.amazon.com/eks/latest/userguide/troubleshooting.html#troubleshoot-container-runtime-network
-func NormalizeARN(arn string) string {
-	parts := strings.Split(arn, "/")
+func NormalizeARN(roleARN string) string {
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return roleARN
+	}
+	parts := strings.Split(parsed.Resource, "/")
 	if len(parts) <= 1 {
-		return arn
+		return roleARN
 	}
-	return fmt.Sprintf("%s/%s", parts[0], parts[len(parts)-1])
+	parsed.Resource = fmt.Sprintf("%s/%s", parts[0], parts[len(parts)-1])
+	return parsed.String()
 }
@@ -0,0 +1,208 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	api "github.com/weaveworks/eksctl/pkg/apis/eksctl.io/v1alpha5"
+	gfn "github.com/weaveworks/goformation/v4/cloudformation"
+	gfnt "github.com/weaveworks/goformation/v4/cloudformation/types"
+)
+
+func ptrBool(b bool) *bool { return &b }
+
+func TestPartitionForRegion(t *testing.T) {
+	tests := []struct {
+		region string
+		want   string
+	}{
+		{"us-east-1", partitionAWS},
+		{"eu-west-2", partitionAWS},
+		{"us-gov-east-1", partitionAWSUsGov},
+		{"us-gov-west-1", partitionAWSUsGov},
+		{"cn-north-1", partitionAWSCN},
+		{"cn-northwest-1", partitionAWSCN},
+		{"us-isob-east-1", partitionAWSISOB},
+		{"us-iso-east-1", partitionAWSISO},
+		{"", partitionAWS},
+	}
+	for _, tt := range tests {
+		if got := partitionForRegion(tt.region); got != tt.want {
+			t.Errorf("partitionForRegion(%q) = %q, want %q", tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestValidateARNPartition(t *testing.T) {
+	tests := []struct {
+		name      string
+		arn       string
+		partition string
+		wantErr   bool
+	}{
+		{"matching aws partition", "arn:aws:iam::123456789012:policy/Foo", partitionAWS, false},
+		{"matching govcloud partition", "arn:aws-us-gov:iam::123456789012:policy/Foo", partitionAWSUsGov, false},
+		{"mismatched partition", "arn:aws:iam::123456789012:policy/Foo", partitionAWSCN, true},
+		{"unparseable arn", "not-an-arn", partitionAWS, true},
+	}
+	for _, tt := range tests {
+		err := validateARNPartition(tt.arn, tt.partition)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateARNPartition(%q, %q) error = %v, wantErr %v", tt.name, tt.arn, tt.partition, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMakeManagedPoliciesExclusive(t *testing.T) {
+	oidcCluster := &api.ClusterIAM{WithOIDC: ptrBool(true)}
+	required := requiredManagedPolicyNames(oidcCluster, false)
+	// Drop exactly one required policy name so only one is reported missing, regardless of how
+	// many names requiredManagedPolicyNames happens to return. An unrelated policy keeps the list
+	// non-empty when only one name is required, so the empty-list error doesn't fire instead.
+	missingOne := append(makeExclusiveTestARNs(required[1:]), "arn:aws:iam::aws:policy/SomeUnrelatedPolicy")
+
+	tests := []struct {
+		name       string
+		iamCluster *api.ClusterIAM
+		iamConfig  *api.NodeGroupIAM
+		managed    bool
+		wantErr    string // substring expected in the error, empty means no error expected
+	}{
+		{
+			name:       "no attachPolicyARNs provided",
+			iamCluster: &api.ClusterIAM{},
+			iamConfig: &api.NodeGroupIAM{
+				AttachPolicyARNsExclusive: ptrBool(true),
+			},
+			wantErr: "no attachPolicyARNs were provided",
+		},
+		{
+			name:       "attachPolicyARN in the wrong partition",
+			iamCluster: &api.ClusterIAM{},
+			iamConfig: &api.NodeGroupIAM{
+				AttachPolicyARNsExclusive: ptrBool(true),
+				AttachPolicyARNs:          []string{"arn:aws-cn:iam::aws:policy/AmazonEKSWorkerNodePolicy"},
+			},
+			wantErr: `expected "aws"`,
+		},
+		{
+			name:       "missing a single required managed policy",
+			iamCluster: oidcCluster,
+			iamConfig: &api.NodeGroupIAM{
+				AttachPolicyARNsExclusive: ptrBool(true),
+				AttachPolicyARNs:          missingOne,
+			},
+			wantErr: "missing required managed policy:",
+		},
+		{
+			name:       "missing multiple required managed policies",
+			iamCluster: oidcCluster,
+			iamConfig: &api.NodeGroupIAM{
+				AttachPolicyARNsExclusive: ptrBool(true),
+				AttachPolicyARNs:          []string{"arn:aws:iam::aws:policy/SomeUnrelatedPolicy"},
+			},
+			managed: true,
+			wantErr: "missing required managed policies:",
+		},
+		{
+			name:       "all required managed policies present",
+			iamCluster: oidcCluster,
+			iamConfig: &api.NodeGroupIAM{
+				AttachPolicyARNsExclusive: ptrBool(true),
+				AttachPolicyARNs:          makeExclusiveTestARNs(required),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := makeManagedPolicies(partitionAWS, tt.iamCluster, tt.iamConfig, tt.managed, false, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %q does not contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeCfnTemplate is a minimal cfnTemplate that records the resources and statements createRole
+// declares, without depending on a real CloudFormation template builder.
+type fakeCfnTemplate struct {
+	resourceCreations map[string]int
+	attaches          []fakeAttachAllowPolicyCall
+}
+
+type fakeAttachAllowPolicyCall struct {
+	name    string
+	refRole *gfnt.Value
+}
+
+func (f *fakeCfnTemplate) newResource(name string, resource gfn.Resource) *gfnt.Value {
+	if f.resourceCreations == nil {
+		f.resourceCreations = map[string]int{}
+	}
+	f.resourceCreations[name]++
+	return gfnt.NewString(name)
+}
+
+func (f *fakeCfnTemplate) attachAllowPolicy(name string, refRole *gfnt.Value, resources interface{}, actions []string, conditions ...iamCondition) {
+	f.attaches = append(f.attaches, fakeAttachAllowPolicyCall{name: name, refRole: refRole})
+}
+
+// TestCreateRoleReusesIRSARoleAcrossStatements is a regression test for a bug where createRole
+// called createAddonServiceAccountRole once per inline statement for an addon with more than one,
+// each call declaring a fresh "IAMServiceAccountPolicy<addon>" CloudFormation resource that
+// overwrote the previous one - so only the last statement's permissions would survive in the
+// rendered template. AutoScaler has two inline statements, so it's used here to exercise the
+// multi-statement case.
+func TestCreateRoleReusesIRSARoleAcrossStatements(t *testing.T) {
+	cfnTemplate := &fakeCfnTemplate{}
+	clusterIAMConfig := &api.ClusterIAM{WithOIDC: ptrBool(true)}
+	iamConfig := &api.NodeGroupIAM{}
+	iamConfig.WithAddonPolicies.AutoScaler = ptrBool(true)
+
+	if err := createRole(cfnTemplate, clusterIAMConfig, iamConfig, "test-cluster", "us-east-1",
+		AddonPolicyAttachModeServiceAccount, "arn:aws:iam::123456789012:oidc-provider/test", "oidc.eks.us-east-1.amazonaws.com/id/test",
+		false, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := cfnTemplate.resourceCreations["IAMServiceAccountPolicyAutoScaler"]; n != 1 {
+		t.Fatalf("expected exactly one IAMServiceAccountPolicyAutoScaler resource to be created, got %d", n)
+	}
+
+	var sawStatements int
+	var refRole *gfnt.Value
+	for _, attach := range cfnTemplate.attaches {
+		if attach.name != "PolicyAutoScaling" && attach.name != "PolicyAutoScalingMutate" {
+			continue
+		}
+		sawStatements++
+		if refRole == nil {
+			refRole = attach.refRole
+		} else if attach.refRole != refRole {
+			t.Fatalf("statement %q was attached to a different IRSA role than the first statement", attach.name)
+		}
+	}
+	if sawStatements != 2 {
+		t.Fatalf("expected both AutoScaler statements to be attached, saw %d", sawStatements)
+	}
+}
+
+// makeExclusiveTestARNs turns required managed policy names into fully-qualified "aws" partition
+// policy ARNs, matching the shape makeManagedPolicies expects in AttachPolicyARNs.
+func makeExclusiveTestARNs(names []string) []string {
+	arns := make([]string, len(names))
+	for i, name := range names {
+		arns[i] = "arn:aws:iam::aws:policy/" + name
+	}
+	return arns
+}
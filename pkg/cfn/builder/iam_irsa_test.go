@@ -0,0 +1,20 @@
+package builder
+
+import "testing"
+
+func TestServiceAccountManifestFor(t *testing.T) {
+	manifest := ServiceAccountManifestFor("CertManager", "arn:aws:iam::123456789012:role/CertManagerRole")
+	if manifest == nil {
+		t.Fatalf("expected a manifest for CertManager, got nil")
+	}
+	if manifest.Namespace != "cert-manager" || manifest.Name != "cert-manager" {
+		t.Errorf("got namespace/name %q/%q, want cert-manager/cert-manager", manifest.Namespace, manifest.Name)
+	}
+	if manifest.RoleARN != "arn:aws:iam::123456789012:role/CertManagerRole" {
+		t.Errorf("RoleARN was not passed through: got %q", manifest.RoleARN)
+	}
+
+	if manifest := ServiceAccountManifestFor("NotARealAddon", "arn:aws:iam::123456789012:role/Whatever"); manifest != nil {
+		t.Errorf("expected nil for an addon with no well-known ServiceAccount, got %+v", manifest)
+	}
+}
@@ -0,0 +1,332 @@
+// Package iampolicies holds the per-addon IAM policy statements that used to live as inline
+// `if api.IsEnabled(...)` branches in pkg/cfn/builder's createRole. Splitting each addon into its
+// own addXPolicies function lets the policies be unit tested and reused across managed, unmanaged,
+// and Fargate node roles without depending on the rest of the CloudFormation template builder.
+package iampolicies
+
+import "fmt"
+
+// Condition is a single IAM Condition block, e.g.
+//   Condition{"StringEquals": {"ec2:CreateAction": "CreateVolume"}}
+// rendered verbatim into the statement's "Condition" key.
+type Condition = map[string]map[string]interface{}
+
+// PolicyAttacher is the subset of the CloudFormation template builder that an addXPolicies function
+// needs: somewhere to attach an allow statement for a given resource/action set, optionally gated
+// by an IAM Condition.
+type PolicyAttacher interface {
+	AttachAllowPolicy(name string, resources interface{}, actions []string, conditions ...Condition)
+}
+
+// PolicyBuilder carries the context addXPolicies functions need to scope a statement's resources
+// down to the current cluster, instead of the historical `Resource: "*"`.
+type PolicyBuilder struct {
+	Attacher PolicyAttacher
+	// ClusterName scopes tag-based resource conditions, e.g. `kubernetes.io/cluster/<ClusterName>`.
+	ClusterName string
+	// LeastPrivilege switches the node-level wildcard actions (`fsx:*`, `elasticfilesystem:*`) for
+	// narrower action lists, splits out their create actions behind a cluster-owned tag condition,
+	// and gates EBS's `ec2:CreateTags`/`ec2:DeleteTags` with an `ec2:CreateAction` condition.
+	LeastPrivilege bool
+	// Partition is the ARN partition the cluster lives in, e.g. "aws", "aws-us-gov", "aws-cn".
+	Partition string
+	// IRSAEnabled is true when this addon's policy is being attached to its own dedicated IRSA
+	// ServiceAccount role rather than the shared node instance role. Some statements only make
+	// sense on the shared role - e.g. a bootstrap grant that any workload running with the node's
+	// credentials might need once - and become an unnecessary privilege-escalation surface on a
+	// role scoped to a single addon's ServiceAccount, so addXPolicies functions should drop them
+	// when IRSAEnabled is set.
+	IRSAEnabled bool
+}
+
+// partitionOrDefault returns p.Partition, falling back to the public "aws" partition if unset.
+func (p *PolicyBuilder) partitionOrDefault() string {
+	if p.Partition == "" {
+		return "aws"
+	}
+	return p.Partition
+}
+
+// partitionedResource turns a bare ARN resource, e.g. "route53:::hostedzone/*", into a
+// fully-qualified ARN in p's partition, e.g. "arn:aws-us-gov:route53:::hostedzone/*".
+func (p *PolicyBuilder) partitionedResource(resource string) string {
+	return fmt.Sprintf("arn:%s:%s", p.partitionOrDefault(), resource)
+}
+
+// clusterOwnedTag is the resource tag EKS add-ons use to mark resources that belong to this
+// cluster, e.g. `kubernetes.io/cluster/<name>: owned`.
+func (p *PolicyBuilder) clusterOwnedTag() string {
+	return fmt.Sprintf("kubernetes.io/cluster/%s", p.ClusterName)
+}
+
+// AddEBSPolicies attaches the EBS CSI driver's node-level policy: an explicit list of EC2 volume
+// and snapshot actions, with `ec2:CreateTags` and `ec2:DeleteTags` in their own statement. When
+// LeastPrivilege is enabled that tag statement is gated by an `ec2:CreateAction` condition, so the
+// node can only tag resources it just created rather than arbitrary EC2 resources; otherwise it is
+// unconditional.
+func AddEBSPolicies(p *PolicyBuilder) {
+	p.Attacher.AttachAllowPolicy("PolicyEBS", "*", []string{
+		"ec2:AttachVolume",
+		"ec2:CreateSnapshot",
+		"ec2:CreateVolume",
+		"ec2:DeleteSnapshot",
+		"ec2:DeleteVolume",
+		"ec2:DescribeAvailabilityZones",
+		"ec2:DescribeInstances",
+		"ec2:DescribeSnapshots",
+		"ec2:DescribeTags",
+		"ec2:DescribeVolumes",
+		"ec2:DescribeVolumesModifications",
+		"ec2:DetachVolume",
+		"ec2:ModifyVolume",
+	})
+
+	tagActions := []string{"ec2:CreateTags", "ec2:DeleteTags"}
+	if !p.LeastPrivilege {
+		p.Attacher.AttachAllowPolicy("PolicyEBSTags", "*", tagActions)
+		return
+	}
+	p.Attacher.AttachAllowPolicy("PolicyEBSTags", "*", tagActions, Condition{
+		"StringEquals": {"ec2:CreateAction": []string{"CreateVolume", "CreateSnapshot"}},
+	})
+}
+
+// AddFSXPolicies attaches the FSx CSI driver's policy, narrowing from `fsx:*` to the explicit
+// action list the driver needs when LeastPrivilege is enabled. `fsx:CreateFileSystem` is split into
+// its own statement gated by a cluster-owned resource tag, so the role can only create filesystems
+// it tags as belonging to this cluster. The `iam:CreateServiceLinkedRole` bootstrap statement is
+// dropped under LeastPrivilege, since it's only needed once to auto-create the FSx service-linked
+// role, and under IRSAEnabled, since granting `iam:AttachRolePolicy`/`PutRolePolicy` to a role
+// scoped to a single ServiceAccount is an unnecessary privilege-escalation risk the shared node
+// role doesn't carry.
+func AddFSXPolicies(p *PolicyBuilder) {
+	if !p.LeastPrivilege {
+		p.Attacher.AttachAllowPolicy("PolicyFSX", "*", []string{"fsx:*"})
+	} else {
+		p.Attacher.AttachAllowPolicy("PolicyFSX", "*", []string{
+			"fsx:DescribeFileSystems",
+			"fsx:DeleteFileSystem",
+			"fsx:TagResource",
+		})
+		p.Attacher.AttachAllowPolicy("PolicyFSXCreateFileSystem", "*", []string{"fsx:CreateFileSystem"}, Condition{
+			"StringEquals": {fmt.Sprintf("aws:RequestTag/%s", p.clusterOwnedTag()): "owned"},
+		})
+	}
+
+	if !p.LeastPrivilege && !p.IRSAEnabled {
+		p.Attacher.AttachAllowPolicy("PolicyServiceLinkRole", p.partitionedResource("iam::*:role/aws-service-role/*"),
+			[]string{
+				"iam:CreateServiceLinkedRole",
+				"iam:AttachRolePolicy",
+				"iam:PutRolePolicy",
+			},
+		)
+	}
+}
+
+// AddEFSPolicies attaches the EFS CSI driver's node-level policy, narrowing from
+// `elasticfilesystem:*` to the explicit action list the driver needs when LeastPrivilege is
+// enabled. `elasticfilesystem:CreateAccessPoint` is split into its own statement gated by a
+// cluster-owned resource tag, so the node can only create access points it tags as belonging to
+// this cluster.
+func AddEFSPolicies(p *PolicyBuilder) {
+	if !p.LeastPrivilege {
+		p.Attacher.AttachAllowPolicy("PolicyEFS", "*", []string{"elasticfilesystem:*"})
+	} else {
+		p.Attacher.AttachAllowPolicy("PolicyEFS", "*", []string{
+			"elasticfilesystem:DescribeAccessPoints",
+			"elasticfilesystem:DescribeFileSystems",
+			"elasticfilesystem:DescribeMountTargets",
+			"elasticfilesystem:DeleteAccessPoint",
+		})
+		p.Attacher.AttachAllowPolicy("PolicyEFSCreateAccessPoint", "*", []string{"elasticfilesystem:CreateAccessPoint"}, Condition{
+			"StringEquals": {fmt.Sprintf("aws:RequestTag/%s", p.clusterOwnedTag()): "owned"},
+		})
+	}
+	p.Attacher.AttachAllowPolicy("PolicyEFSEC2", "*",
+		[]string{
+			"ec2:DescribeSubnets",
+			"ec2:CreateNetworkInterface",
+			"ec2:DescribeNetworkInterfaces",
+			"ec2:DeleteNetworkInterface",
+			"ec2:ModifyNetworkInterfaceAttribute",
+			"ec2:DescribeNetworkInterfaceAttribute",
+		},
+	)
+}
+
+// AddCertManagerPolicies attaches cert-manager's Route53 DNS-01 policy. When externalDNSEnabled is
+// true, the hosted-zone listing statement is widened to also cover what ExternalDNS needs, so that
+// enabling both addons together doesn't require a second, overlapping statement.
+func AddCertManagerPolicies(p *PolicyBuilder, externalDNSEnabled bool) {
+	p.Attacher.AttachAllowPolicy("PolicyCertManagerChangeSet", p.partitionedResource("route53:::hostedzone/*"),
+		[]string{"route53:ChangeResourceRecordSets"},
+	)
+
+	hostedZonePolicy := []string{
+		"route53:ListResourceRecordSets",
+		"route53:ListHostedZonesByName",
+	}
+	if externalDNSEnabled {
+		hostedZonePolicy = append(hostedZonePolicy, "route53:ListHostedZones", "route53:ListTagsForResource")
+	}
+	p.Attacher.AttachAllowPolicy("PolicyCertManagerHostedZones", "*", hostedZonePolicy)
+
+	p.Attacher.AttachAllowPolicy("PolicyCertManagerGetChange", p.partitionedResource("route53:::change/*"),
+		[]string{"route53:GetChange"},
+	)
+}
+
+// AddExternalDNSPolicies attaches external-dns's Route53 policy. It's only used when CertManager is
+// disabled - enabling both addons together routes through AddCertManagerPolicies instead, which
+// already covers everything ExternalDNS needs.
+func AddExternalDNSPolicies(p *PolicyBuilder) {
+	p.Attacher.AttachAllowPolicy("PolicyExternalDNSChangeSet", p.partitionedResource("route53:::hostedzone/*"),
+		[]string{"route53:ChangeResourceRecordSets"},
+	)
+	p.Attacher.AttachAllowPolicy("PolicyExternalDNSHostedZones", "*",
+		[]string{
+			"route53:ListHostedZones",
+			"route53:ListResourceRecordSets",
+			"route53:ListTagsForResource",
+		},
+	)
+}
+
+// appMeshActions are shared by App Mesh and its preview channel; the only difference between the
+// two is the trailing `appmesh(-preview):*` wildcard.
+var appMeshActions = []string{
+	"servicediscovery:CreateService",
+	"servicediscovery:DeleteService",
+	"servicediscovery:GetService",
+	"servicediscovery:GetInstance",
+	"servicediscovery:RegisterInstance",
+	"servicediscovery:DeregisterInstance",
+	"servicediscovery:ListInstances",
+	"servicediscovery:ListNamespaces",
+	"servicediscovery:ListServices",
+	"servicediscovery:GetInstancesHealthStatus",
+	"servicediscovery:UpdateInstanceCustomHealthStatus",
+	"servicediscovery:GetOperation",
+	"route53:GetHealthCheck",
+	"route53:CreateHealthCheck",
+	"route53:UpdateHealthCheck",
+	"route53:ChangeResourceRecordSets",
+	"route53:DeleteHealthCheck",
+}
+
+// AddAppMeshPolicies attaches the App Mesh controller's policy, or its preview-channel equivalent
+// when preview is true.
+func AddAppMeshPolicies(p *PolicyBuilder, preview bool) {
+	cfnName, wildcard := "PolicyAppMesh", "appmesh:*"
+	if preview {
+		cfnName, wildcard = "PolicyAppMeshPreview", "appmesh-preview:*"
+	}
+	p.Attacher.AttachAllowPolicy(cfnName, "*", append(append([]string{}, appMeshActions...), wildcard))
+}
+
+// AddALBIngressPolicies attaches the AWS Load Balancer Controller's policy. `ec2:CreateTags` and
+// `ec2:DeleteTags` are split into their own statement gated by an `ec2:CreateAction` condition,
+// mirroring the controller's own recommended IAM policy, so the role can only tag a security group
+// it just created rather than arbitrary EC2 resources. Delete*/Modify* load balancer actions are
+// similarly split and scoped to load balancers tagged as belonging to this cluster, so the role
+// can't tear down or reconfigure an ALB it didn't create. `iam:CreateServiceLinkedRole` is dropped
+// under IRSAEnabled for the same reason it's dropped from AddFSXPolicies' bootstrap statement: it's
+// an unnecessary privilege-escalation surface on a role scoped to a single ServiceAccount.
+func AddALBIngressPolicies(p *PolicyBuilder) {
+	actions := []string{
+		"acm:DescribeCertificate",
+		"acm:ListCertificates",
+		"acm:GetCertificate",
+		"ec2:AuthorizeSecurityGroupIngress",
+		"ec2:CreateSecurityGroup",
+		"ec2:DeleteSecurityGroup",
+		"ec2:DescribeAccountAttributes",
+		"ec2:DescribeAddresses",
+		"ec2:DescribeInstances",
+		"ec2:DescribeInstanceStatus",
+		"ec2:DescribeInternetGateways",
+		"ec2:DescribeNetworkInterfaces",
+		"ec2:DescribeSecurityGroups",
+		"ec2:DescribeSubnets",
+		"ec2:DescribeTags",
+		"ec2:DescribeVpcs",
+		"ec2:ModifyInstanceAttribute",
+		"ec2:ModifyNetworkInterfaceAttribute",
+		"ec2:RevokeSecurityGroupIngress",
+		"elasticloadbalancing:AddListenerCertificates",
+		"elasticloadbalancing:AddTags",
+		"elasticloadbalancing:CreateListener",
+		"elasticloadbalancing:CreateLoadBalancer",
+		"elasticloadbalancing:CreateRule",
+		"elasticloadbalancing:CreateTargetGroup",
+		"elasticloadbalancing:DeregisterTargets",
+		"elasticloadbalancing:DescribeListenerCertificates",
+		"elasticloadbalancing:DescribeListeners",
+		"elasticloadbalancing:DescribeLoadBalancers",
+		"elasticloadbalancing:DescribeLoadBalancerAttributes",
+		"elasticloadbalancing:DescribeRules",
+		"elasticloadbalancing:DescribeSSLPolicies",
+		"elasticloadbalancing:DescribeTags",
+		"elasticloadbalancing:DescribeTargetGroups",
+		"elasticloadbalancing:DescribeTargetGroupAttributes",
+		"elasticloadbalancing:DescribeTargetHealth",
+		"elasticloadbalancing:RegisterTargets",
+		"elasticloadbalancing:RemoveListenerCertificates",
+		"elasticloadbalancing:RemoveTags",
+		"elasticloadbalancing:SetIpAddressType",
+		"elasticloadbalancing:SetSecurityGroups",
+		"elasticloadbalancing:SetSubnets",
+		"elasticloadbalancing:SetWebACL",
+		"iam:GetServerCertificate",
+		"iam:ListServerCertificates",
+		"waf-regional:GetWebACLForResource",
+		"waf-regional:GetWebACL",
+		"waf-regional:AssociateWebACL",
+		"waf-regional:DisassociateWebACL",
+		"tag:GetResources",
+		"tag:TagResources",
+		"waf:GetWebACL",
+		"wafv2:GetWebACL",
+		"wafv2:GetWebACLForResource",
+		"wafv2:AssociateWebACL",
+		"wafv2:DisassociateWebACL",
+		"shield:DescribeProtection",
+		"shield:GetSubscriptionState",
+		"shield:DeleteProtection",
+		"shield:CreateProtection",
+		"shield:DescribeSubscription",
+		"shield:ListProtections",
+	}
+	if !p.IRSAEnabled {
+		actions = append(actions, "iam:CreateServiceLinkedRole")
+	}
+	p.Attacher.AttachAllowPolicy("PolicyALBIngress", "*", actions)
+
+	p.Attacher.AttachAllowPolicy("PolicyALBIngressTags", "*",
+		[]string{
+			"ec2:CreateTags",
+			"ec2:DeleteTags",
+		},
+		Condition{
+			"StringEquals": {"ec2:CreateAction": "CreateSecurityGroup"},
+		},
+	)
+
+	p.Attacher.AttachAllowPolicy("PolicyALBIngressMutate", "*",
+		[]string{
+			"elasticloadbalancing:DeleteListener",
+			"elasticloadbalancing:DeleteLoadBalancer",
+			"elasticloadbalancing:DeleteRule",
+			"elasticloadbalancing:DeleteTargetGroup",
+			"elasticloadbalancing:ModifyListener",
+			"elasticloadbalancing:ModifyLoadBalancerAttributes",
+			"elasticloadbalancing:ModifyRule",
+			"elasticloadbalancing:ModifyTargetGroup",
+			"elasticloadbalancing:ModifyTargetGroupAttributes",
+		},
+		Condition{
+			"StringEquals": {"elasticloadbalancing:ResourceTag/elbv2.k8s.aws/cluster": p.ClusterName},
+		},
+	)
+}
@@ -0,0 +1,151 @@
+package iampolicies
+
+import "testing"
+
+// fakeAttacher records every AttachAllowPolicy call so tests can assert on the statements an
+// addXPolicies function produced without needing a real CloudFormation template.
+type fakeAttacher struct {
+	calls []fakeAttachCall
+}
+
+type fakeAttachCall struct {
+	name       string
+	resources  interface{}
+	actions    []string
+	conditions []Condition
+}
+
+func (f *fakeAttacher) AttachAllowPolicy(name string, resources interface{}, actions []string, conditions ...Condition) {
+	f.calls = append(f.calls, fakeAttachCall{name: name, resources: resources, actions: actions, conditions: conditions})
+}
+
+func (f *fakeAttacher) call(name string) *fakeAttachCall {
+	for i, c := range f.calls {
+		if c.name == name {
+			return &f.calls[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeAttacher) hasAction(name, action string) bool {
+	c := f.call(name)
+	if c == nil {
+		return false
+	}
+	for _, a := range c.actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAddEBSPolicies(t *testing.T) {
+	for _, leastPrivilege := range []bool{false, true} {
+		attacher := &fakeAttacher{}
+		AddEBSPolicies(&PolicyBuilder{Attacher: attacher, LeastPrivilege: leastPrivilege})
+
+		tags := attacher.call("PolicyEBSTags")
+		if tags == nil {
+			t.Fatalf("LeastPrivilege=%v: expected a PolicyEBSTags statement", leastPrivilege)
+		}
+		if leastPrivilege && len(tags.conditions) == 0 {
+			t.Errorf("LeastPrivilege=true: expected PolicyEBSTags to be gated by an ec2:CreateAction condition")
+		}
+		if !leastPrivilege && len(tags.conditions) != 0 {
+			t.Errorf("LeastPrivilege=false: expected PolicyEBSTags to be unconditional")
+		}
+	}
+}
+
+func TestAddFSXPolicies(t *testing.T) {
+	tests := []struct {
+		leastPrivilege, irsaEnabled bool
+		wantServiceLinkRole         bool
+	}{
+		{false, false, true},
+		{false, true, false},
+		{true, false, false},
+		{true, true, false},
+	}
+	for _, tt := range tests {
+		attacher := &fakeAttacher{}
+		AddFSXPolicies(&PolicyBuilder{Attacher: attacher, LeastPrivilege: tt.leastPrivilege, IRSAEnabled: tt.irsaEnabled, ClusterName: "test"})
+
+		got := attacher.call("PolicyServiceLinkRole") != nil
+		if got != tt.wantServiceLinkRole {
+			t.Errorf("LeastPrivilege=%v IRSAEnabled=%v: PolicyServiceLinkRole present = %v, want %v",
+				tt.leastPrivilege, tt.irsaEnabled, got, tt.wantServiceLinkRole)
+		}
+
+		if tt.leastPrivilege {
+			if attacher.call("PolicyFSXCreateFileSystem") == nil {
+				t.Errorf("LeastPrivilege=true: expected PolicyFSXCreateFileSystem to be split out")
+			}
+			if attacher.hasAction("PolicyFSX", "fsx:*") {
+				t.Errorf("LeastPrivilege=true: expected fsx:* to be narrowed")
+			}
+		} else if !attacher.hasAction("PolicyFSX", "fsx:*") {
+			t.Errorf("LeastPrivilege=false: expected unnarrowed fsx:*")
+		}
+	}
+}
+
+func TestAddCertManagerPolicies(t *testing.T) {
+	for _, externalDNSEnabled := range []bool{false, true} {
+		attacher := &fakeAttacher{}
+		AddCertManagerPolicies(&PolicyBuilder{Attacher: attacher, Partition: "aws"}, externalDNSEnabled)
+
+		hasExternalDNSActions := attacher.hasAction("PolicyCertManagerHostedZones", "route53:ListHostedZones")
+		if hasExternalDNSActions != externalDNSEnabled {
+			t.Errorf("externalDNSEnabled=%v: PolicyCertManagerHostedZones covers route53:ListHostedZones = %v, want %v",
+				externalDNSEnabled, hasExternalDNSActions, externalDNSEnabled)
+		}
+	}
+}
+
+func TestAddExternalDNSPolicies(t *testing.T) {
+	attacher := &fakeAttacher{}
+	AddExternalDNSPolicies(&PolicyBuilder{Attacher: attacher, Partition: "aws"})
+	if attacher.call("PolicyExternalDNSChangeSet") == nil || attacher.call("PolicyExternalDNSHostedZones") == nil {
+		t.Fatalf("expected both PolicyExternalDNSChangeSet and PolicyExternalDNSHostedZones to be attached")
+	}
+}
+
+func TestAddAppMeshPolicies(t *testing.T) {
+	tests := []struct {
+		preview      bool
+		wantCFNName  string
+		wantWildcard string
+	}{
+		{false, "PolicyAppMesh", "appmesh:*"},
+		{true, "PolicyAppMeshPreview", "appmesh-preview:*"},
+	}
+	for _, tt := range tests {
+		attacher := &fakeAttacher{}
+		AddAppMeshPolicies(&PolicyBuilder{Attacher: attacher}, tt.preview)
+		if !attacher.hasAction(tt.wantCFNName, tt.wantWildcard) {
+			t.Errorf("preview=%v: expected %s to include %s", tt.preview, tt.wantCFNName, tt.wantWildcard)
+		}
+	}
+}
+
+func TestAddALBIngressPolicies(t *testing.T) {
+	for _, irsaEnabled := range []bool{false, true} {
+		attacher := &fakeAttacher{}
+		AddALBIngressPolicies(&PolicyBuilder{Attacher: attacher, IRSAEnabled: irsaEnabled, ClusterName: "test"})
+
+		hasCreateServiceLinkedRole := attacher.hasAction("PolicyALBIngress", "iam:CreateServiceLinkedRole")
+		if hasCreateServiceLinkedRole == irsaEnabled {
+			t.Errorf("IRSAEnabled=%v: iam:CreateServiceLinkedRole present = %v, want %v", irsaEnabled, hasCreateServiceLinkedRole, !irsaEnabled)
+		}
+
+		if attacher.call("PolicyALBIngressTags") == nil {
+			t.Fatalf("IRSAEnabled=%v: expected PolicyALBIngressTags to be attached", irsaEnabled)
+		}
+		if len(attacher.call("PolicyALBIngressTags").conditions) == 0 {
+			t.Errorf("IRSAEnabled=%v: expected PolicyALBIngressTags to be gated by an ec2:CreateAction condition", irsaEnabled)
+		}
+	}
+}